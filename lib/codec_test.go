@@ -0,0 +1,116 @@
+package vegeta
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestMsgpackCodecRoundTrip(t *testing.T) {
+	want := &Result{
+		Code:      200,
+		Timestamp: time.Now().Round(time.Microsecond),
+		Latency:   150 * time.Millisecond,
+		BytesOut:  42,
+		BytesIn:   1024,
+		Error:     "",
+	}
+
+	var buf bytes.Buffer
+	enc := MsgpackCodec.NewEncoder(&buf)
+	if err := enc.Encode(want); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var got Result
+	dec := MsgpackCodec.NewDecoder(&buf)
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if !got.Timestamp.Equal(want.Timestamp) || got.Code != want.Code ||
+		got.Latency != want.Latency || got.BytesOut != want.BytesOut ||
+		got.BytesIn != want.BytesIn || got.Error != want.Error {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestGobCodecRoundTrip(t *testing.T) {
+	want := &Result{Code: 500, Latency: time.Second, Error: "timeout"}
+
+	var buf bytes.Buffer
+	enc := GobCodec.NewEncoder(&buf)
+	if err := enc.Encode(want); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var got Result
+	dec := GobCodec.NewDecoder(&buf)
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if got.Code != want.Code || got.Latency != want.Latency || got.Error != want.Error {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestSniffCodecDetectsMsgpack(t *testing.T) {
+	var buf bytes.Buffer
+	if err := MsgpackCodec.NewEncoder(&buf).Encode(&Result{Code: 200}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	codec, r, err := SniffCodec(&buf)
+	if err != nil {
+		t.Fatalf("SniffCodec: %v", err)
+	}
+	if codec != MsgpackCodec {
+		t.Fatalf("SniffCodec detected %v, want MsgpackCodec", codec)
+	}
+
+	var got Result
+	if err := codec.NewDecoder(r).Decode(&got); err != nil {
+		t.Fatalf("Decode after sniff: %v", err)
+	}
+	if got.Code != 200 {
+		t.Fatalf("got.Code = %d, want 200", got.Code)
+	}
+}
+
+func TestSniffCodecDefaultsToGob(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GobCodec.NewEncoder(&buf).Encode(&Result{Code: 200}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	codec, r, err := SniffCodec(&buf)
+	if err != nil {
+		t.Fatalf("SniffCodec: %v", err)
+	}
+	if codec != GobCodec {
+		t.Fatalf("SniffCodec detected %v, want GobCodec", codec)
+	}
+
+	var got Result
+	if err := codec.NewDecoder(r).Decode(&got); err != nil {
+		t.Fatalf("Decode after sniff: %v", err)
+	}
+	if got.Code != 200 {
+		t.Fatalf("got.Code = %d, want 200", got.Code)
+	}
+}
+
+func TestSniffCodecEmpty(t *testing.T) {
+	codec, r, err := SniffCodec(bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("SniffCodec on empty reader: %v", err)
+	}
+	if codec != GobCodec {
+		t.Fatalf("SniffCodec detected %v, want GobCodec", codec)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("Read on replayed empty reader = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,55 @@
+package vegeta
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	for _, c := range []CompressionCodec{NoCompression, GzipCompression, ZstdCompression} {
+		var buf bytes.Buffer
+		w, err := c.NewWriter(&buf)
+		if err != nil {
+			t.Fatalf("%v: NewWriter: %v", c, err)
+		}
+		if _, err := w.Write([]byte("hello vegeta")); err != nil {
+			t.Fatalf("%v: Write: %v", c, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%v: Close: %v", c, err)
+		}
+
+		r, err := DetectCompression(&buf)
+		if err != nil {
+			t.Fatalf("%v: DetectCompression: %v", c, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%v: ReadAll: %v", c, err)
+		}
+		if string(got) != "hello vegeta" {
+			t.Fatalf("%v: got %q, want %q", c, got, "hello vegeta")
+		}
+	}
+}
+
+func TestDetectCompressionShortInput(t *testing.T) {
+	r, err := DetectCompression(bytes.NewReader([]byte("ab")))
+	if err != nil {
+		t.Fatalf("DetectCompression on short input: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "ab" {
+		t.Fatalf("got %q, want %q", got, "ab")
+	}
+}
+
+func TestNewWriterUnknownCodec(t *testing.T) {
+	if _, err := CompressionCodec(99).NewWriter(&bytes.Buffer{}); err != errUnknownCompression {
+		t.Fatalf("NewWriter with unknown codec = %v, want errUnknownCompression", err)
+	}
+}
@@ -0,0 +1,79 @@
+package vegeta
+
+import "math"
+
+// point is a single (x, y) sample used by the LTTB downsampling algorithm.
+type point struct {
+	x float64
+	y float64
+	r *Result
+}
+
+// lttb reduces pts to at most n points using the Largest Triangle Three
+// Buckets algorithm, which preserves the visual shape of a series (peaks,
+// troughs) far better than naive decimation. If len(pts) <= n or n <= 0, pts
+// is returned unchanged.
+func lttb(pts []point, n int) []point {
+	if n <= 0 || len(pts) <= n {
+		return pts
+	}
+	if n == 1 {
+		return []point{pts[0]}
+	}
+	if n == 2 {
+		return []point{pts[0], pts[len(pts)-1]}
+	}
+
+	sampled := make([]point, 0, n)
+	sampled = append(sampled, pts[0])
+
+	// Bucket size for the middle points (everything between the fixed
+	// first and last points).
+	bucketSize := float64(len(pts)-2) / float64(n-2)
+
+	a := 0
+	for i := 0; i < n-2; i++ {
+		bucketStart := int(float64(i)*bucketSize) + 1
+		bucketEnd := int(float64(i+1)*bucketSize) + 1
+		if bucketEnd > len(pts)-1 {
+			bucketEnd = len(pts) - 1
+		}
+
+		nextStart := bucketEnd
+		nextEnd := int(float64(i+2)*bucketSize) + 1
+		if nextEnd > len(pts) {
+			nextEnd = len(pts)
+		}
+		if nextStart >= nextEnd {
+			nextEnd = nextStart + 1
+		}
+
+		var avgX, avgY float64
+		for j := nextStart; j < nextEnd && j < len(pts); j++ {
+			avgX += pts[j].x
+			avgY += pts[j].y
+		}
+		count := float64(nextEnd - nextStart)
+		if count > 0 {
+			avgX /= count
+			avgY /= count
+		}
+
+		pa := pts[a]
+		maxArea := -1.0
+		maxIdx := bucketStart
+		for j := bucketStart; j < bucketEnd; j++ {
+			area := math.Abs((pa.x-avgX)*(pts[j].y-pa.y)-(pa.x-pts[j].x)*(avgY-pa.y)) * 0.5
+			if area > maxArea {
+				maxArea = area
+				maxIdx = j
+			}
+		}
+
+		sampled = append(sampled, pts[maxIdx])
+		a = maxIdx
+	}
+
+	sampled = append(sampled, pts[len(pts)-1])
+	return sampled
+}
@@ -0,0 +1,84 @@
+package vegeta
+
+import "testing"
+
+func TestLTTBUnchangedWhenSmall(t *testing.T) {
+	pts := []point{{x: 0, y: 0}, {x: 1, y: 1}, {x: 2, y: 2}}
+	got := lttb(pts, 10)
+	if len(got) != len(pts) {
+		t.Fatalf("lttb with n <= len(pts) should return input unchanged, got %d points, want %d", len(got), len(pts))
+	}
+}
+
+func TestLTTBEmpty(t *testing.T) {
+	got := lttb(nil, 10)
+	if len(got) != 0 {
+		t.Fatalf("lttb(nil, 10) = %v, want empty", got)
+	}
+}
+
+func TestLTTBOnePointRespectsBudget(t *testing.T) {
+	pts := make([]point, 100)
+	for i := range pts {
+		pts[i] = point{x: float64(i), y: float64(i)}
+	}
+
+	got := lttb(pts, 1)
+	if len(got) != 1 {
+		t.Fatalf("lttb(pts, 1) returned %d points, want 1", len(got))
+	}
+	if got[0] != pts[0] {
+		t.Errorf("got[0] = %v, want %v", got[0], pts[0])
+	}
+}
+
+func TestLTTBKeepsFirstAndLast(t *testing.T) {
+	pts := make([]point, 1000)
+	for i := range pts {
+		pts[i] = point{x: float64(i), y: float64(i)}
+	}
+
+	got := lttb(pts, 100)
+	if len(got) != 100 {
+		t.Fatalf("len(got) = %d, want 100", len(got))
+	}
+	if got[0] != pts[0] {
+		t.Errorf("first point = %v, want %v", got[0], pts[0])
+	}
+	if got[len(got)-1] != pts[len(pts)-1] {
+		t.Errorf("last point = %v, want %v", got[len(got)-1], pts[len(pts)-1])
+	}
+}
+
+func TestLTTBPreservesSpike(t *testing.T) {
+	pts := make([]point, 1000)
+	for i := range pts {
+		pts[i] = point{x: float64(i), y: 1}
+	}
+	// A single sharp spike in the middle of an otherwise flat series.
+	spikeIdx := 500
+	pts[spikeIdx].y = 1000
+
+	got := lttb(pts, 50)
+
+	found := false
+	for _, p := range got {
+		if p.y == 1000 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("lttb dropped the spike at x=%d; got series max value %v", spikeIdx, maxY(got))
+	}
+}
+
+func maxY(pts []point) float64 {
+	max := 0.0
+	for _, p := range pts {
+		if p.y > max {
+			max = p.y
+		}
+	}
+	return max
+}
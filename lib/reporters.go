@@ -2,13 +2,17 @@ package vegeta
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
-	"text/tabwriter"
 	"text/template"
+	"time"
 )
 
+// defaultPlotPoints is the target number of points ReportPlot keeps per
+// series (OK and ERR) after LTTB downsampling.
+const defaultPlotPoints = 2000
+
 // Reporter is an interface defining Report computation.
 type Reporter interface {
 	Report(Results) ([]byte, error)
@@ -23,55 +27,163 @@ type ReporterFunc func(Results) ([]byte, error)
 func (f ReporterFunc) Report(r Results) ([]byte, error) { return f(r) }
 
 // ReportText returns a computed Metrics struct as aligned, formatted text.
+// It's built on top of the same online accumulator as NewStreamingText, so
+// a single codepath backs both the batch and streaming reporters.
 var ReportText ReporterFunc = func(r Results) ([]byte, error) {
-	m := NewMetrics(r)
-	out := &bytes.Buffer{}
-
-	w := tabwriter.NewWriter(out, 0, 8, 2, '\t', tabwriter.StripEscape)
-	fmt.Fprintf(w, "Requests\t[total]\t%d\n", m.Requests)
-	fmt.Fprintf(w, "Duration\t[total, attack, wait]\t%s, %s, %s\n", m.Duration+m.Wait, m.Duration, m.Wait)
-	fmt.Fprintf(w, "Latencies\t[mean, 50, 95, 99, max]\t%s, %s, %s, %s, %s\n",
-		m.Latencies.Mean, m.Latencies.P50, m.Latencies.P95, m.Latencies.P99, m.Latencies.Max)
-	fmt.Fprintf(w, "Bytes In\t[total, mean]\t%d, %.2f\n", m.BytesIn.Total, m.BytesIn.Mean)
-	fmt.Fprintf(w, "Bytes Out\t[total, mean]\t%d, %.2f\n", m.BytesOut.Total, m.BytesOut.Mean)
-	fmt.Fprintf(w, "Success\t[ratio]\t%.2f%%\n", m.Success*100)
-	fmt.Fprintf(w, "Status Codes\t[code:count]\t")
-	for code, count := range m.StatusCodes {
-		fmt.Fprintf(w, "%s:%d  ", code, count)
+	sr := NewStreamingText()
+	for _, res := range r {
+		if err := sr.Push(res); err != nil {
+			return nil, err
+		}
 	}
-	fmt.Fprintln(w, "\nError Set:")
-	for _, err := range m.Errors {
-		fmt.Fprintln(w, err)
+	return sr.Close()
+}
+
+// ReportJSON writes a computed Metrics struct to as JSON. It's built on top
+// of the same online accumulator as NewStreamingJSON, so a single codepath
+// backs both the batch and streaming reporters.
+var ReportJSON ReporterFunc = func(r Results) ([]byte, error) {
+	sr := NewStreamingJSON()
+	for _, res := range r {
+		if err := sr.Push(res); err != nil {
+			return nil, err
+		}
 	}
+	return sr.Close()
+}
 
-	if err := w.Flush(); err != nil {
-		return []byte{}, err
+// ReportPrometheus writes a computed Metrics struct as Prometheus text
+// exposition format (https://prometheus.io/docs/instrumenting/exposition_formats/),
+// suitable for scraping or pushing to a Pushgateway from CI. It's built on
+// top of the same online accumulator as NewStreamingPrometheus, so a single
+// codepath backs both the batch and streaming reporters and the emitted
+// P50/P95/P99/max always agree with ReportText/ReportJSON on the same result
+// stream. The error set is not part of the Prometheus output.
+var ReportPrometheus ReporterFunc = func(r Results) ([]byte, error) {
+	sr := NewStreamingPrometheus()
+	for _, res := range r {
+		if err := sr.Push(res); err != nil {
+			return nil, err
+		}
 	}
-	return out.Bytes(), nil
+	return sr.Close()
 }
 
-// ReportJSON writes a computed Metrics struct to as JSON
-var ReportJSON ReporterFunc = func(r Results) ([]byte, error) {
-	return json.Marshal(NewMetrics(r))
+// renderPrometheus formats m in Prometheus text exposition format. It's the
+// shared rendering step behind both ReportPrometheus and
+// streamingPrometheus.Close.
+func renderPrometheus(m Metrics) ([]byte, error) {
+	out := &bytes.Buffer{}
+
+	fmt.Fprintf(out, "# HELP vegeta_requests_total Total number of requests sent.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_requests_total counter\n")
+	fmt.Fprintf(out, "vegeta_requests_total %d\n", m.Requests)
+
+	fmt.Fprintf(out, "# HELP vegeta_success_ratio Fraction of requests that succeeded.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_success_ratio gauge\n")
+	fmt.Fprintf(out, "vegeta_success_ratio %f\n", m.Success)
+
+	fmt.Fprintf(out, "# HELP vegeta_bytes_in_total Total bytes received.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_bytes_in_total counter\n")
+	fmt.Fprintf(out, "vegeta_bytes_in_total %d\n", m.BytesIn.Total)
+
+	fmt.Fprintf(out, "# HELP vegeta_bytes_out_total Total bytes sent.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_bytes_out_total counter\n")
+	fmt.Fprintf(out, "vegeta_bytes_out_total %d\n", m.BytesOut.Total)
+
+	fmt.Fprintf(out, "# HELP vegeta_status_codes_total Number of requests by status code.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_status_codes_total counter\n")
+	codes := make([]string, 0, len(m.StatusCodes))
+	for code := range m.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(out, "vegeta_status_codes_total{code=%q} %d\n", code, m.StatusCodes[code])
+	}
+
+	fmt.Fprintf(out, "# HELP vegeta_latency_seconds Latency distribution of requests.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_latency_seconds summary\n")
+	fmt.Fprintf(out, "vegeta_latency_seconds{quantile=\"0.5\"} %f\n", m.Latencies.P50.Seconds())
+	fmt.Fprintf(out, "vegeta_latency_seconds{quantile=\"0.95\"} %f\n", m.Latencies.P95.Seconds())
+	fmt.Fprintf(out, "vegeta_latency_seconds{quantile=\"0.99\"} %f\n", m.Latencies.P99.Seconds())
+	fmt.Fprintf(out, "vegeta_latency_seconds_sum %f\n", m.Latencies.Mean.Seconds()*float64(m.Requests))
+	fmt.Fprintf(out, "vegeta_latency_seconds_count %d\n", m.Requests)
+
+	fmt.Fprintf(out, "# HELP vegeta_latency_seconds_max Maximum observed latency.\n")
+	fmt.Fprintf(out, "# TYPE vegeta_latency_seconds_max gauge\n")
+	fmt.Fprintf(out, "vegeta_latency_seconds_max %f\n", m.Latencies.Max.Seconds())
+
+	return out.Bytes(), nil
 }
 
 // ReportPlot builds up a self contained HTML page with an interactive plot
 // of the latencies of the requests. Built with http://dygraphs.com/
-var ReportPlot ReporterFunc = func(r Results) ([]byte, error) {
-	series := &bytes.Buffer{}
-	for i, point := 0, ""; i < len(r); i++ {
-		point = "[" + strconv.FormatFloat(
-			r[i].Timestamp.Sub(r[0].Timestamp).Seconds(), 'f', -1, 32) + ","
+//
+// Series are downsampled to defaultPlotPoints points per OK/ERR series using
+// LTTB so that multi-hour, high-rate attacks still produce a plot a browser
+// can render. See ReportPlotN to control the target point count.
+var ReportPlot = ReportPlotN(defaultPlotPoints)
 
-		if r[i].Error == "" {
-			point += "NaN," + strconv.FormatFloat(r[i].Latency.Seconds()*1000, 'f', -1, 32) + "],"
-		} else {
-			point += strconv.FormatFloat(r[i].Latency.Seconds()*1000, 'f', -1, 32) + ",NaN],"
+// ReportPlotN returns a ReporterFunc that behaves like ReportPlot but
+// downsamples the OK and ERR latency series independently to at most max
+// points each (using LTTB) before rendering. Downsampling each series on its
+// own keeps error spikes visible even when they're a tiny fraction of a
+// mostly-successful run. A max <= 0 disables downsampling.
+func ReportPlotN(max int) ReporterFunc {
+	return func(r Results) ([]byte, error) {
+		var ok, errored []point
+		for _, res := range r {
+			p := point{
+				x: res.Timestamp.Sub(firstTimestamp(r)).Seconds(),
+				y: res.Latency.Seconds() * 1000,
+				r: res,
+			}
+			if res.Error == "" {
+				ok = append(ok, p)
+			} else {
+				errored = append(errored, p)
+			}
+		}
+
+		if max > 0 {
+			ok = lttb(ok, max)
+			errored = lttb(errored, max)
+		}
+
+		plotted := make([]point, 0, len(ok)+len(errored))
+		plotted = append(plotted, ok...)
+		plotted = append(plotted, errored...)
+		sort.Slice(plotted, func(i, j int) bool {
+			return plotted[i].r.Timestamp.Before(plotted[j].r.Timestamp)
+		})
+
+		results := make(Results, 0, len(plotted))
+		for _, p := range plotted {
+			results = append(results, p.r)
 		}
 
-		series.WriteString(point)
+		return renderPlot(results, plotted)
+	}
+}
+
+// renderPlot builds the self-contained HTML plot page shared by ReportPlotN
+// and streamingPlot.Close: it writes plotted as a dygraphs series and lists
+// results in the results table below it. Both callers pass the same
+// downsampled/reservoir-sampled Results that back plotted, not the full
+// input slice, so the results table stays bounded to the same point budget
+// as the graph for a multi-million-result run.
+func renderPlot(results Results, plotted []point) ([]byte, error) {
+	series := &bytes.Buffer{}
+	for _, p := range plotted {
+		series.WriteString("[" + strconv.FormatFloat(p.x, 'f', -1, 32) + ",")
+		if p.r.Error == "" {
+			series.WriteString("NaN," + strconv.FormatFloat(p.y, 'f', -1, 32) + "],")
+		} else {
+			series.WriteString(strconv.FormatFloat(p.y, 'f', -1, 32) + ",NaN],")
+		}
 	}
-	// Remove trailing commas
+	// Remove trailing comma
 	if series.Len() > 0 {
 		series.Truncate(series.Len() - 1)
 	}
@@ -85,17 +197,25 @@ var ReportPlot ReporterFunc = func(r Results) ([]byte, error) {
 	}{
 		JsSrc:   string(dygraphJSLibSrc()),
 		Series:  series.String(),
-		Results: r,
+		Results: results,
 	}
 
-	err := plotsTemplate.Execute(&out, ctx)
-	if err != nil {
+	if err := plotsTemplate.Execute(&out, ctx); err != nil {
 		return nil, err
 	}
 
 	return out.Bytes(), nil
 }
 
+// firstTimestamp returns the timestamp of the first result, or the zero
+// time if r is empty.
+func firstTimestamp(r Results) (t time.Time) {
+	if len(r) > 0 {
+		t = r[0].Timestamp
+	}
+	return t
+}
+
 var plotsTemplate *template.Template = template.Must(template.New("plot").Parse(`<!doctype>
 <html>
 <head>
@@ -1,3 +1,5 @@
+//go:generate msgp -file=$GOFILE -o=results_gen.go
+
 package vegeta
 
 import (
@@ -22,10 +24,22 @@ type Result struct {
 	Error     string
 }
 
-// Collect concurrently reads Results from multiple io.Readers until all of
-// them return io.EOF. Each read Result is passed to the returned Results channel
-// while errors will be put in the returned error channel.
+// Collect concurrently reads Results from multiple io.Readers, decoding them
+// with GobCodec, until all of them return io.EOF. Each read Result is passed
+// to the returned Results channel while errors will be put in the returned
+// error channel. It's kept as a thin GobCodec wrapper around CollectCodec so
+// existing callers built against the pre-ResultCodec signature keep working.
 func Collect(in ...io.Reader) (<-chan *Result, <-chan error) {
+	return CollectCodec(GobCodec, in...)
+}
+
+// CollectCodec concurrently reads Results from multiple io.Readers, decoding
+// them with codec, until all of them return io.EOF. Each read Result is
+// passed to the returned Results channel while errors will be put in the
+// returned error channel. A nil codec sniffs each source independently with
+// SniffCodec instead of assuming a fixed format, which is how report
+// auto-detects old gob result files mixed with newer msgpack ones.
+func CollectCodec(codec ResultCodec, in ...io.Reader) (<-chan *Result, <-chan error) {
 	var wg sync.WaitGroup
 	resc := make(chan *Result)
 	errs := make(chan error)
@@ -33,7 +47,24 @@ func Collect(in ...io.Reader) (<-chan *Result, <-chan error) {
 	for i := range in {
 		wg.Add(1)
 		go func(src io.Reader) {
-			dec := gob.NewDecoder(src)
+			src, err := DetectCompression(src)
+			if err != nil {
+				errs <- err
+				wg.Done()
+				return
+			}
+
+			srcCodec := codec
+			if srcCodec == nil {
+				srcCodec, src, err = SniffCodec(src)
+				if err != nil {
+					errs <- err
+					wg.Done()
+					return
+				}
+			}
+
+			dec := srcCodec.NewDecoder(src)
 			for {
 				var r Result
 				if err := dec.Decode(&r); err != nil {
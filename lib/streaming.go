@@ -0,0 +1,286 @@
+package vegeta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dgryski/go-gk"
+)
+
+// maxStreamingErrors bounds the distinct error set a StreamingReporter keeps
+// in memory. Soak tests that hammer a broken target can otherwise produce an
+// unbounded number of distinct error strings (e.g. ones that embed a
+// timestamp or connection id).
+const maxStreamingErrors = 256
+
+// StreamingReporter computes a report incrementally, one Result at a time,
+// so a caller never has to hold an entire Results slice (and therefore an
+// entire attack's worth of hits) in memory. Push is called once per Result
+// as it's read off the wire; Close finalizes and renders the report.
+type StreamingReporter interface {
+	Push(*Result) error
+	Close() ([]byte, error)
+}
+
+// onlineMetrics accumulates the statistics behind a Metrics value online:
+// Welford's method for latency mean, a Greenwald-Khanna sketch
+// (github.com/dgryski/go-gk) for quantiles, running sums for bytes and
+// status codes, and a bounded-size distinct error set. Memory use is
+// O(sketch size), not O(requests).
+type onlineMetrics struct {
+	requests   uint64
+	successes  uint64
+	earliest   time.Time
+	latest     time.Time
+	wait       time.Duration
+	latencyAvg float64 // seconds, Welford running mean
+	latencyMax time.Duration
+	sketch     *gk.Stream
+
+	bytesInTotal  uint64
+	bytesOutTotal uint64
+	statusCodes   map[string]int
+
+	errors   []string
+	errorSet map[string]bool
+}
+
+func newOnlineMetrics() *onlineMetrics {
+	return &onlineMetrics{
+		sketch:      gk.New(0.001),
+		statusCodes: map[string]int{},
+		errorSet:    map[string]bool{},
+	}
+}
+
+func (m *onlineMetrics) push(r *Result) {
+	m.requests++
+
+	if m.earliest.IsZero() || r.Timestamp.Before(m.earliest) {
+		m.earliest = r.Timestamp
+	}
+	if r.Timestamp.After(m.latest) || m.latest.IsZero() {
+		m.latest = r.Timestamp
+		m.wait = r.Latency
+	}
+
+	secs := r.Latency.Seconds()
+	delta := secs - m.latencyAvg
+	m.latencyAvg += delta / float64(m.requests)
+	if r.Latency > m.latencyMax {
+		m.latencyMax = r.Latency
+	}
+	m.sketch.Insert(secs)
+
+	m.bytesInTotal += r.BytesIn
+	m.bytesOutTotal += r.BytesOut
+	m.statusCodes[strconv.Itoa(r.Code)]++
+
+	if r.Error == "" {
+		m.successes++
+	} else if !m.errorSet[r.Error] && len(m.errors) < maxStreamingErrors {
+		m.errorSet[r.Error] = true
+		m.errors = append(m.errors, r.Error)
+	}
+}
+
+func (m *onlineMetrics) metrics() Metrics {
+	quantile := func(q float64) time.Duration {
+		if m.requests == 0 {
+			return 0
+		}
+		return time.Duration(m.sketch.Query(q) * float64(time.Second))
+	}
+
+	var success float64
+	if m.requests > 0 {
+		success = float64(m.successes) / float64(m.requests)
+	}
+
+	var bytesInMean, bytesOutMean float64
+	if m.requests > 0 {
+		bytesInMean = float64(m.bytesInTotal) / float64(m.requests)
+		bytesOutMean = float64(m.bytesOutTotal) / float64(m.requests)
+	}
+
+	return Metrics{
+		Requests: m.requests,
+		Duration: m.latest.Sub(m.earliest),
+		Wait:     m.wait,
+		Latencies: LatencyMetrics{
+			Mean: time.Duration(m.latencyAvg * float64(time.Second)),
+			P50:  quantile(0.50),
+			P95:  quantile(0.95),
+			P99:  quantile(0.99),
+			Max:  m.latencyMax,
+		},
+		BytesIn:     ByteMetrics{Total: m.bytesInTotal, Mean: bytesInMean},
+		BytesOut:    ByteMetrics{Total: m.bytesOutTotal, Mean: bytesOutMean},
+		Success:     success,
+		StatusCodes: m.statusCodes,
+		Errors:      m.errors,
+	}
+}
+
+// streamingText is the StreamingReporter behind ReportText.
+type streamingText struct{ m *onlineMetrics }
+
+// NewStreamingText returns a StreamingReporter that renders the same output
+// as ReportText without holding every Result in memory.
+func NewStreamingText() StreamingReporter { return &streamingText{m: newOnlineMetrics()} }
+
+func (s *streamingText) Push(r *Result) error { s.m.push(r); return nil }
+
+func (s *streamingText) Close() ([]byte, error) {
+	m := s.m.metrics()
+	out := &bytes.Buffer{}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, '\t', tabwriter.StripEscape)
+	fmt.Fprintf(w, "Requests\t[total]\t%d\n", m.Requests)
+	fmt.Fprintf(w, "Duration\t[total, attack, wait]\t%s, %s, %s\n", m.Duration+m.Wait, m.Duration, m.Wait)
+	fmt.Fprintf(w, "Latencies\t[mean, 50, 95, 99, max]\t%s, %s, %s, %s, %s\n",
+		m.Latencies.Mean, m.Latencies.P50, m.Latencies.P95, m.Latencies.P99, m.Latencies.Max)
+	fmt.Fprintf(w, "Bytes In\t[total, mean]\t%d, %.2f\n", m.BytesIn.Total, m.BytesIn.Mean)
+	fmt.Fprintf(w, "Bytes Out\t[total, mean]\t%d, %.2f\n", m.BytesOut.Total, m.BytesOut.Mean)
+	fmt.Fprintf(w, "Success\t[ratio]\t%.2f%%\n", m.Success*100)
+	fmt.Fprintf(w, "Status Codes\t[code:count]\t")
+	for code, count := range m.StatusCodes {
+		fmt.Fprintf(w, "%s:%d  ", code, count)
+	}
+	fmt.Fprintln(w, "\nError Set:")
+	for _, err := range m.Errors {
+		fmt.Fprintln(w, err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return []byte{}, err
+	}
+	return out.Bytes(), nil
+}
+
+// streamingJSON is the StreamingReporter behind ReportJSON.
+type streamingJSON struct{ m *onlineMetrics }
+
+// NewStreamingJSON returns a StreamingReporter that renders the same output
+// as ReportJSON without holding every Result in memory.
+func NewStreamingJSON() StreamingReporter { return &streamingJSON{m: newOnlineMetrics()} }
+
+func (s *streamingJSON) Push(r *Result) error { s.m.push(r); return nil }
+
+func (s *streamingJSON) Close() ([]byte, error) { return json.Marshal(s.m.metrics()) }
+
+// streamingPrometheus is the StreamingReporter behind ReportPrometheus.
+type streamingPrometheus struct{ m *onlineMetrics }
+
+// NewStreamingPrometheus returns a StreamingReporter that renders the same
+// output as ReportPrometheus without holding every Result in memory.
+func NewStreamingPrometheus() StreamingReporter { return &streamingPrometheus{m: newOnlineMetrics()} }
+
+func (s *streamingPrometheus) Push(r *Result) error { s.m.push(r); return nil }
+
+func (s *streamingPrometheus) Close() ([]byte, error) { return renderPrometheus(s.m.metrics()) }
+
+// streamingPlot is the StreamingReporter behind ReportPlot in streaming
+// mode. Since a soak test can produce far more points than fit in a plot, it
+// keeps the OK and ERR series down to at most max points each with
+// reservoir sampling as Results arrive, rather than buffering everything
+// and running LTTB at the end.
+type streamingPlot struct {
+	max             int
+	first           time.Time
+	ok, errored     []point
+	okSeen, errSeen int
+}
+
+// NewStreamingPlot returns a StreamingReporter that renders the same kind of
+// page as ReportPlotN(max) without holding every Result in memory: it keeps a
+// bounded reservoir sample of at most max points per OK/ERR series, so the
+// points it plots are a random sample rather than the ones LTTB picks. As with
+// ReportPlotN, a max <= 0 disables downsampling and keeps every point.
+func NewStreamingPlot(max int) StreamingReporter { return &streamingPlot{max: max} }
+
+func (s *streamingPlot) Push(r *Result) error {
+	if s.first.IsZero() {
+		s.first = r.Timestamp
+	}
+	p := point{
+		x: r.Timestamp.Sub(s.first).Seconds(),
+		y: r.Latency.Seconds() * 1000,
+		r: r,
+	}
+	if r.Error == "" {
+		s.okSeen++
+		reservoirInsert(&s.ok, p, s.okSeen, s.max)
+	} else {
+		s.errSeen++
+		reservoirInsert(&s.errored, p, s.errSeen, s.max)
+	}
+	return nil
+}
+
+// reservoirInsert implements Algorithm R: the first max items are always
+// kept, after that item number seen replaces a uniformly random existing
+// slot with probability max/seen. A max <= 0 means downsampling is disabled
+// (matching ReportPlotN's contract), so every point is appended unbounded.
+func reservoirInsert(reservoir *[]point, p point, seen, max int) {
+	if max <= 0 || len(*reservoir) < max {
+		*reservoir = append(*reservoir, p)
+		return
+	}
+	if j := rand.Intn(seen); j < max {
+		(*reservoir)[j] = p
+	}
+}
+
+func (s *streamingPlot) Close() ([]byte, error) {
+	plotted := make([]point, 0, len(s.ok)+len(s.errored))
+	plotted = append(plotted, s.ok...)
+	plotted = append(plotted, s.errored...)
+	sort.Slice(plotted, func(i, j int) bool {
+		return plotted[i].r.Timestamp.Before(plotted[j].r.Timestamp)
+	})
+
+	results := make(Results, 0, len(plotted))
+	for _, p := range plotted {
+		results = append(results, p.r)
+	}
+
+	return renderPlot(results, plotted)
+}
+
+// StreamCollect reads Results decoded by codec off in, pushing each into sr
+// as it arrives, then finalizes and returns sr's report. Because it drains
+// CollectCodec's channel directly, memory use stays O(sketch size) rather
+// than O(requests) even for a day-long soak test. A nil codec sniffs each
+// source independently, same as CollectCodec.
+func StreamCollect(sr StreamingReporter, codec ResultCodec, in ...io.Reader) ([]byte, error) {
+	resc, errs := CollectCodec(codec, in...)
+	for resc != nil || errs != nil {
+		select {
+		case r, ok := <-resc:
+			if !ok {
+				resc = nil
+				continue
+			}
+			if err := sr.Push(r); err != nil {
+				return nil, err
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return sr.Close()
+}
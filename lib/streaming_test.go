@@ -0,0 +1,161 @@
+package vegeta
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReservoirInsertBoundedSize(t *testing.T) {
+	var reservoir []point
+	for i := 0; i < 10000; i++ {
+		reservoirInsert(&reservoir, point{x: float64(i), y: float64(i)}, i+1, 100)
+	}
+	if len(reservoir) != 100 {
+		t.Fatalf("len(reservoir) = %d, want 100", len(reservoir))
+	}
+}
+
+func TestReservoirInsertUnboundedWhenMaxIsZero(t *testing.T) {
+	var reservoir []point
+	for i := 0; i < 50; i++ {
+		reservoirInsert(&reservoir, point{x: float64(i), y: float64(i)}, i+1, 0)
+	}
+	if len(reservoir) != 50 {
+		t.Fatalf("len(reservoir) = %d, want 50 (max <= 0 disables downsampling)", len(reservoir))
+	}
+}
+
+func TestStreamingPlotRoutesAndBoundsOutput(t *testing.T) {
+	base := time.Unix(0, 0)
+	sr := NewStreamingPlot(2)
+	p := sr.(*streamingPlot)
+
+	for i := 0; i < 10; i++ {
+		r := &Result{Timestamp: base.Add(time.Duration(i) * time.Second), Latency: time.Duration(i) * time.Millisecond}
+		if i%3 == 0 {
+			r.Error = "boom"
+		}
+		if err := sr.Push(r); err != nil {
+			t.Fatalf("Push: %v", err)
+		}
+	}
+
+	if !p.first.Equal(base) {
+		t.Errorf("first = %v, want %v (the first pushed timestamp)", p.first, base)
+	}
+	if len(p.ok) > 2 {
+		t.Errorf("len(ok) = %d, want at most 2 (reservoir max)", len(p.ok))
+	}
+	if len(p.errored) > 2 {
+		t.Errorf("len(errored) = %d, want at most 2 (reservoir max)", len(p.errored))
+	}
+	if p.okSeen != 6 || p.errSeen != 4 {
+		t.Errorf("okSeen, errSeen = %d, %d, want 6, 4", p.okSeen, p.errSeen)
+	}
+
+	out, err := sr.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(out) == 0 {
+		t.Fatal("Close returned empty output")
+	}
+}
+
+func TestStreamingPlotCloseSortsByTimestamp(t *testing.T) {
+	sr := NewStreamingPlot(0) // unbounded, so ordering isn't disturbed by reservoir eviction
+	base := time.Unix(1000, 0)
+
+	// Push out of chronological order: middle, earliest, latest.
+	results := []*Result{
+		{Timestamp: base, Latency: 100 * time.Millisecond},
+		{Timestamp: base.Add(-10 * time.Second), Latency: 200 * time.Millisecond},
+		{Timestamp: base.Add(10 * time.Second), Latency: 300 * time.Millisecond},
+	}
+	for _, r := range results {
+		if err := sr.Push(r); err != nil {
+			t.Fatalf("Push: %v", err)
+		}
+	}
+
+	out, err := sr.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Close must sort plotted points by Timestamp before rendering, so the
+	// point for the 200ms latency (earliest) should appear before 100ms
+	// (middle) which should appear before 300ms (latest). All three are OK
+	// results, so each renders as "NaN,<ms>]" in the dygraph series.
+	i200, i100, i300 := strings.Index(string(out), "NaN,200]"), strings.Index(string(out), "NaN,100]"), strings.Index(string(out), "NaN,300]")
+	if i200 < 0 || i100 < 0 || i300 < 0 {
+		t.Fatalf("expected all three latency values in output, got indices %d, %d, %d", i200, i100, i300)
+	}
+	if !(i200 < i100 && i100 < i300) {
+		t.Errorf("output not sorted by timestamp: indices 200ms=%d, 100ms=%d, 300ms=%d", i200, i100, i300)
+	}
+}
+
+// TestReportPrometheusAgreesWithReportText asserts that ReportPrometheus,
+// run over the same Results, reports the same p50 and max latency as
+// ReportJSON, which shares ReportText's online accumulator. Before this, ReportPrometheus computed exact quantiles via
+// NewMetrics while ReportText used the GK sketch behind onlineMetrics, so
+// the two reporters could silently disagree on the same input.
+func TestReportPrometheusAgreesWithReportText(t *testing.T) {
+	now := time.Unix(0, 0)
+	var results Results
+	for i := 0; i < 500; i++ {
+		r := &Result{
+			Code:      200,
+			Timestamp: now.Add(time.Duration(i) * time.Millisecond),
+			Latency:   time.Duration(i) * time.Millisecond,
+		}
+		if i%10 == 0 {
+			r.Code = 500
+			r.Error = "boom"
+		}
+		results = append(results, r)
+	}
+
+	jsonOut, err := ReportJSON.Report(results)
+	if err != nil {
+		t.Fatalf("ReportJSON: %v", err)
+	}
+	var wantMetrics Metrics
+	if err := json.Unmarshal(jsonOut, &wantMetrics); err != nil {
+		t.Fatalf("unmarshal ReportJSON output: %v", err)
+	}
+
+	promOut, err := ReportPrometheus.Report(results)
+	if err != nil {
+		t.Fatalf("ReportPrometheus: %v", err)
+	}
+
+	wantP50 := wantMetrics.Latencies.P50.Seconds()
+	if got := promText(promOut, `vegeta_latency_seconds{quantile="0.5"}`); got != wantP50 {
+		t.Errorf("prometheus p50 = %v, want %v (from ReportJSON)", got, wantP50)
+	}
+
+	wantMax := wantMetrics.Latencies.Max.Seconds()
+	if got := promText(promOut, "vegeta_latency_seconds_max"); got != wantMax {
+		t.Errorf("prometheus max = %v, want %v (from ReportJSON)", got, wantMax)
+	}
+}
+
+// promText extracts the float64 value following the given metric prefix in
+// Prometheus exposition text, for use in tests only.
+func promText(out []byte, prefix string) float64 {
+	for _, line := range strings.Split(string(out), "\n") {
+		if rest := strings.TrimPrefix(line, prefix); rest != line {
+			v, err := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+			if err != nil {
+				return 0
+			}
+			return v
+		}
+	}
+	return 0
+}